@@ -0,0 +1,62 @@
+// Command pascal-repl is an interactive lex-and-print loop: it reads a
+// line from stdin, feeds it to a fresh Lexer, and prints the resulting
+// tokens, so contributors can experiment with a Dialect without editing
+// test.txt.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Irwin1985/LexerCollection/go/lexer"
+)
+
+const prompt = ">> "
+
+func main() {
+	dialectName := flag.String("dialect", "standard", "Pascal dialect to lex with: standard, turbo, or object")
+	flag.Parse()
+
+	dialect, err := dialectByName(*dialectName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("pascal-repl (%s) -- Ctrl+D to exit\n", dialect.Name)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print(prompt)
+		if !scanner.Scan() {
+			return
+		}
+
+		lex := lexer.NewLexerWithDialect("<stdin>", strings.NewReader(scanner.Text()), dialect)
+		for tok := range lex.Tokens() {
+			if tok.Type == lexer.EOF {
+				break
+			}
+			fmt.Printf("  %+v\n", tok)
+		}
+		for _, e := range lex.Errors {
+			fmt.Println("  error:", e.Error())
+		}
+	}
+}
+
+func dialectByName(name string) (*lexer.Dialect, error) {
+	switch name {
+	case "standard":
+		return lexer.StandardPascal, nil
+	case "turbo":
+		return lexer.TurboPascal, nil
+	case "object":
+		return lexer.ObjectPascal, nil
+	default:
+		return nil, fmt.Errorf("unknown dialect %q (want standard, turbo, or object)", name)
+	}
+}
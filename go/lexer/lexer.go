@@ -0,0 +1,708 @@
+// Package lexer implements a hand-written scanner for Pascal and its
+// dialects.
+package lexer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type TokenType string
+
+const (
+	// single character token types
+	PLUS      = "+"
+	MINUS     = "-"
+	MUL       = "*"
+	FLOAT_DIV = "/"
+	LPAREN    = "("
+	RPAREN    = ")"
+	LBRACE    = "{"
+	RBRACE    = "}"
+	SEMI      = ";"
+	DOT       = "."
+	COLON     = ":"
+	COMMA     = ","
+	ASSIGN    = ":="
+	LESS      = "<"
+	// block of reserved words
+	PROGRAM     = "PROGRAM"
+	INTEGER     = "INTEGER"
+	REAL        = "REAL"
+	INTEGER_DIV = "DIV"
+	VAR         = "VAR"
+	PROCEDURE   = "PROCEDURE"
+	BEGIN       = "BEGIN"
+	END         = "END"
+	// misc
+	IDENT         = "IDENT"
+	INTEGER_CONST = "INTEGER_CONST"
+	REAL_CONST    = "REAL_CONST"
+	EOF           = "EOF"
+	// dialect-specific operators, only recognized when a Dialect enables them
+	LE    = "<="
+	GE    = ">="
+	NE    = "<>"
+	RANGE = ".."
+	// NONE is the rune/byte-count sentinel used once input is exhausted,
+	// mirroring the EOF rune go/scanner uses internally.
+	NONE = -1
+	// bom is U+FEFF; when it's the first rune of a source file it is a
+	// byte-order mark, not content, and is skipped. Written as an escape
+	// rather than the raw rune: Go only permits a literal BOM byte as the
+	// very first byte of a source file, so embedding it here would make
+	// this file itself illegal.
+	bom = '\uFEFF'
+)
+
+// Reserved keywords, shared by the built-in dialects. Lookups are always
+// done against the uppercase form; Dialect.CaseSensitive controls whether
+// the source identifier is folded to uppercase first.
+var keywords = map[string]TokenType{
+	"PROGRAM":   PROGRAM,
+	"INTEGER":   INTEGER,
+	"REAL":      REAL,
+	"DIV":       INTEGER_DIV,
+	"VAR":       VAR,
+	"PROCEDURE": PROCEDURE,
+	"BEGIN":     BEGIN,
+	"END":       END,
+}
+
+// DialectOperator is a multi-character operator a Dialect recognizes beyond
+// the lexer's built-in single-character tokens.
+type DialectOperator struct {
+	Op   string
+	Type TokenType
+}
+
+// CommentDelims is a pair of opening/closing block-comment delimiters, e.g.
+// {"{", "}"} or {"(*", "*)"}.
+type CommentDelims struct {
+	Open  string
+	Close string
+}
+
+// Dialect configures the vocabulary a Lexer accepts, so the same scanner
+// can drive Standard Pascal, Turbo Pascal, Object Pascal, or any variant
+// in between without recompiling.
+type Dialect struct {
+	Name string
+	// Keywords maps the uppercase spelling of every reserved word to its
+	// token type.
+	Keywords map[string]TokenType
+	// CaseSensitive, when false, folds identifiers to uppercase before
+	// matching them against Keywords (Turbo Pascal is case-insensitive).
+	CaseSensitive bool
+	// BlockComments lists the delimiter pairs this dialect treats as
+	// comments, tried in order.
+	BlockComments []CommentDelims
+	// LineComment is the prefix that starts a comment running to end of
+	// line (e.g. "//"). Empty disables line comments.
+	LineComment string
+	// ExtraOperators lists multi-character operators beyond the built-in
+	// single-character set, tried in order before falling back to them.
+	ExtraOperators []DialectOperator
+}
+
+// StandardPascal is the original ISO 7185 dialect: case-sensitive, with
+// only `{ }` block comments and no extra operators.
+var StandardPascal = &Dialect{
+	Name:          "Standard Pascal",
+	Keywords:      keywords,
+	CaseSensitive: true,
+	BlockComments: []CommentDelims{{"{", "}"}},
+}
+
+// TurboPascal is case-insensitive, adds `(* *)` and `//` comments, and
+// recognizes the relational/range operators Standard Pascal lacks.
+var TurboPascal = &Dialect{
+	Name:          "Turbo Pascal",
+	Keywords:      keywords,
+	CaseSensitive: false,
+	BlockComments: []CommentDelims{{"{", "}"}, {"(*", "*)"}},
+	LineComment:   "//",
+	ExtraOperators: []DialectOperator{
+		{"<=", LE}, {">=", GE}, {"<>", NE}, {"..", RANGE},
+	},
+}
+
+// ObjectPascal extends TurboPascal's lexical rules; Delphi's object-oriented
+// keywords live at the parser level, so lexically it is identical today.
+var ObjectPascal = &Dialect{
+	Name:          "Object Pascal",
+	Keywords:      keywords,
+	CaseSensitive: false,
+	BlockComments: []CommentDelims{{"{", "}"}, {"(*", "*)"}},
+	LineComment:   "//",
+	ExtraOperators: []DialectOperator{
+		{"<=", LE}, {">=", GE}, {"<>", NE}, {"..", RANGE},
+	},
+}
+
+// Pos is a compact reference to a byte offset in some File registered with
+// a FileSet, mirroring go/token.Pos. It resolves to a full Position only on
+// demand, so tokens can carry just an int instead of a filename/line/column
+// triple each.
+type Pos int
+
+// NoPos means "no position", the zero Pos.
+const NoPos Pos = 0
+
+// Position is the human-readable form of a Pos: where it points within a
+// named file.
+type Position struct {
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+func (p Position) String() string {
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// File tracks the line-start offsets of a single source as a Lexer
+// consumes it, so a Pos can later be resolved to a line and column. Its
+// size grows with each byte the lexer reads, since Lexer scans a streaming
+// io.Reader whose length isn't known up front; only the most recently
+// added File in a FileSet should still be growing at any given time.
+type File struct {
+	name  string
+	base  Pos
+	size  int
+	lines []int // byte offsets where each line starts; lines[0] is always 0
+}
+
+func (f *File) Name() string { return f.name }
+func (f *File) Base() Pos    { return f.base }
+func (f *File) Size() int    { return f.size }
+
+func (f *File) grow(n int) { f.size += n }
+
+// AddLine records that a new line starts at offset, the byte offset of its
+// first character. advance() calls this as it crosses each line break, so
+// offsets must be supplied in increasing order.
+func (f *File) AddLine(offset int) {
+	if n := len(f.lines); n == 0 || f.lines[n-1] < offset {
+		f.lines = append(f.lines, offset)
+	}
+}
+
+// Position resolves p, which must have come from this File (directly or
+// via its FileSet), to a filename/line/column triple.
+func (f *File) Position(p Pos) Position {
+	offset := int(p - f.base)
+	i := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > offset }) - 1
+	if i < 0 {
+		i = 0
+	}
+	return Position{Filename: f.name, Offset: offset, Line: i + 1, Column: offset - f.lines[i] + 1}
+}
+
+// FileSet hands out a disjoint range of Pos values to each File it tracks,
+// go/token style, so positions from different source files can share one
+// space instead of colliding at the same offsets.
+type FileSet struct {
+	base  int
+	files []*File
+}
+
+// NewFileSet creates an empty FileSet. Pos 0 is reserved for NoPos, so the
+// first File registered starts at base 1.
+func NewFileSet() *FileSet {
+	return &FileSet{base: 1}
+}
+
+// AddFile registers a new source file and returns the File used to track
+// its line breaks and resolve its positions. Its Pos range starts right
+// after the previously added file's.
+func (s *FileSet) AddFile(filename string) *File {
+	if n := len(s.files); n > 0 {
+		prev := s.files[n-1]
+		s.base = int(prev.base) + prev.size + 1
+	}
+	f := &File{name: filename, base: Pos(s.base), lines: []int{0}}
+	s.files = append(s.files, f)
+	return f
+}
+
+// Position resolves p against whichever File its range falls into.
+func (s *FileSet) Position(p Pos) Position {
+	for _, f := range s.files {
+		if p >= f.base && int(p-f.base) <= f.size {
+			return f.Position(p)
+		}
+	}
+	return Position{}
+}
+
+type Token struct {
+	Type  TokenType
+	Value interface{}
+	// Lit is the raw source text of an IDENT, INTEGER_CONST, or REAL_CONST
+	// token. It is parsed lazily, on demand, via IntVal/FloatVal instead of
+	// eagerly at scan time.
+	Lit string
+	Pos Pos
+}
+
+// IntVal parses Lit as the integer value of an INTEGER_CONST token.
+func (t Token) IntVal() (int64, error) {
+	return strconv.ParseInt(t.Lit, 10, 64)
+}
+
+// FloatVal parses Lit as the floating-point value of a REAL_CONST token.
+func (t Token) FloatVal() (float64, error) {
+	return strconv.ParseFloat(t.Lit, 64)
+}
+
+// LexError describes a single diagnostic produced while scanning, in the
+// spirit of go/scanner's Error: a position plus a human-readable message.
+type LexError struct {
+	Line    int
+	Column  int
+	Message string
+	Snippet string
+}
+
+func (e LexError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// LexErrorList collects every LexError recorded during a scan so a parser
+// or IDE can report them all at once instead of bailing out on the first.
+type LexErrorList []LexError
+
+func (l LexErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0].Error(), len(l)-1)
+}
+
+type Lexer struct {
+	reader      *bufio.Reader
+	Dialect     *Dialect
+	CurrentChar rune
+	// Pos is the byte offset of CurrentChar within the source.
+	Pos int
+	// curSize is the byte length of CurrentChar, needed to compute Pos for
+	// the rune that follows it.
+	curSize int
+	file    *File
+	// Errors collects every diagnostic produced while scanning. The lexer
+	// never aborts on its own; it records the problem, recovers, and keeps
+	// producing tokens.
+	Errors []LexError
+	// ErrorHandler, if set, is invoked synchronously for every diagnostic
+	// in addition to it being appended to Errors.
+	ErrorHandler func(LexError)
+}
+
+// NewLexerInFileSet scans text pulled on demand from r using the
+// vocabulary described by d, recording filename's positions in fset so
+// they can be resolved or compared alongside positions from other files
+// sharing the same FileSet.
+func NewLexerInFileSet(fset *FileSet, filename string, r io.Reader, d *Dialect) *Lexer {
+	l := &Lexer{reader: bufio.NewReader(r), Dialect: d, file: fset.AddFile(filename)}
+	l.nextChar()
+	if l.CurrentChar == bom {
+		// A leading BOM is not part of the source: drop its bytes from Pos
+		// and the File's size so the first real character is offset 0.
+		l.file.size -= l.curSize
+		l.curSize = 0
+		l.nextChar()
+	}
+	return l
+}
+
+// NewLexerWithDialect scans text pulled on demand from r using the
+// vocabulary described by d, so sources larger than memory (or interactive
+// streams) can be lexed without buffering the whole input up front.
+func NewLexerWithDialect(filename string, r io.Reader, d *Dialect) *Lexer {
+	return NewLexerInFileSet(NewFileSet(), filename, r, d)
+}
+
+// NewLexer scans using StandardPascal, the dialect the lexer has always
+// understood.
+func NewLexer(filename string, r io.Reader) *Lexer {
+	return NewLexerWithDialect(filename, r, StandardPascal)
+}
+
+// NewLexerString is a convenience for lexing an in-memory string that
+// isn't associated with a file on disk.
+func NewLexerString(text string) *Lexer {
+	return NewLexer("", strings.NewReader(text))
+}
+
+// File returns the File this lexer is recording line breaks into, so
+// callers can resolve a Token's Pos to a filename:line:column Position.
+func (l *Lexer) File() *File {
+	return l.file
+}
+
+// tokenPos returns the Pos of CurrentChar, to be snapshotted before a
+// token's first character is consumed.
+func (l *Lexer) tokenPos() Pos {
+	return l.file.base + Pos(l.Pos)
+}
+
+// LookupIdent reports the token type for ident under the lexer's current
+// Dialect, folding to uppercase first unless the dialect is case-sensitive.
+func (l *Lexer) LookupIdent(ident string) TokenType {
+	key := ident
+	if !l.Dialect.CaseSensitive {
+		key = strings.ToUpper(key)
+	}
+	if tok, ok := l.Dialect.Keywords[key]; ok {
+		return tok
+	}
+	return IDENT
+}
+
+// HasErrors reports whether the lexer has recorded any diagnostics so far.
+func (l *Lexer) HasErrors() bool {
+	return len(l.Errors) > 0
+}
+
+// Err returns the accumulated diagnostics as an error, or nil if the lexer
+// hasn't recorded any.
+func (l *Lexer) Err() error {
+	if !l.HasErrors() {
+		return nil
+	}
+	return LexErrorList(l.Errors)
+}
+
+// error records a diagnostic at CurrentChar's position and notifies
+// ErrorHandler, if any. It does not stop scanning: callers are expected to
+// advance past the offending input themselves so the lexer can recover.
+func (l *Lexer) error(message, snippet string) {
+	pos := l.file.Position(l.tokenPos())
+	e := LexError{Line: pos.Line, Column: pos.Column, Message: message, Snippet: snippet}
+	l.Errors = append(l.Errors, e)
+	if l.ErrorHandler != nil {
+		l.ErrorHandler(e)
+	}
+}
+
+// advance consumes CurrentChar and decodes the rune after it. A line
+// terminator — "\n", "\r", or "\r\n" — is consumed as a single logical
+// advance: each line-terminator branch below calls nextChar() once per
+// terminator byte, then reports the new line to file once. Any other rune
+// is consumed with a single nextChar().
+func (l *Lexer) advance() {
+	switch l.CurrentChar {
+	case '\r':
+		l.nextChar()
+		if l.CurrentChar == '\n' { // "\r\n" is one line terminator, not two
+			l.nextChar()
+		}
+		l.file.AddLine(l.Pos)
+	case '\n':
+		l.nextChar()
+		l.file.AddLine(l.Pos)
+	default:
+		l.nextChar()
+	}
+}
+
+// nextChar decodes and consumes the next rune from the buffered reader,
+// advancing Pos past the rune that used to be CurrentChar so Pos always
+// names CurrentChar's own offset, not the offset just past it.
+func (l *Lexer) nextChar() {
+	l.Pos += l.curSize
+	r, size := l.readRune()
+	l.file.grow(size)
+	l.CurrentChar = r
+	l.curSize = size
+}
+
+// readRune decodes one UTF-8 rune from the reader without consuming bytes
+// belonging to a rune it hasn't returned yet, so a later peek() can look
+// past it.
+func (l *Lexer) readRune() (rune, int) {
+	b, _ := l.reader.Peek(utf8.UTFMax)
+	if len(b) == 0 {
+		return NONE, 0
+	}
+	r, size := utf8.DecodeRune(b)
+	l.reader.Discard(size)
+	return r, size
+}
+
+// peek returns the rune after CurrentChar without consuming it.
+func (l *Lexer) peek() rune {
+	b, _ := l.reader.Peek(utf8.UTFMax)
+	if len(b) == 0 {
+		return NONE
+	}
+	r, _ := utf8.DecodeRune(b)
+	return r
+}
+
+func (l *Lexer) isSpace(ch rune) bool {
+	return ch == ' '
+}
+
+func (l *Lexer) isDigit(ch rune) bool {
+	return unicode.IsDigit(ch)
+}
+
+func (l *Lexer) isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
+}
+
+func (l *Lexer) skipWhiteSpace() {
+	for l.CurrentChar != NONE && l.isSpace(l.CurrentChar) {
+		l.advance()
+	}
+}
+
+// number and identifier build their literal with a strings.Builder rather
+// than slicing the source directly: a slice-backed Lit, as go/scanner uses
+// over its in-memory []byte, would need a span into a buffer that outlives
+// the token, but the streaming bufio.Reader this lexer reads from only
+// keeps enough of the source around to satisfy the next peek(), not the
+// whole file. The Builder still avoids the O(n^2) cost of repeated string
+// concatenation — each WriteRune is an amortized-O(1) append, with a
+// single copy into the result string at the end — it just isn't the
+// allocation-free slice a buffered reader would allow.
+func (l *Lexer) number() Token {
+	pos := l.tokenPos()
+	var lit strings.Builder
+	for l.CurrentChar != NONE && l.isDigit(l.CurrentChar) {
+		lit.WriteRune(l.CurrentChar)
+		l.advance()
+	}
+	if l.CurrentChar == '.' && l.isDigit(l.peek()) {
+		lit.WriteRune(l.CurrentChar)
+		l.advance() // eat '.'
+		for l.CurrentChar != NONE && l.isDigit(l.CurrentChar) {
+			lit.WriteRune(l.CurrentChar)
+			l.advance()
+		}
+		return Token{Type: REAL_CONST, Lit: lit.String(), Pos: pos}
+	}
+	return Token{Type: INTEGER_CONST, Lit: lit.String(), Pos: pos}
+}
+
+func (l *Lexer) identifier() Token {
+	pos := l.tokenPos()
+	var lit strings.Builder
+	for l.CurrentChar != NONE && l.isLetter(l.CurrentChar) {
+		lit.WriteRune(l.CurrentChar)
+		l.advance()
+	}
+	result := lit.String()
+	return Token{Type: l.LookupIdent(result), Lit: result, Pos: pos}
+}
+
+// lookingAt reports whether s starts at CurrentChar, without consuming
+// anything. s must be ASCII, which holds for every delimiter and operator
+// a Dialect configures.
+func (l *Lexer) lookingAt(s string) bool {
+	if s == "" || rune(s[0]) != l.CurrentChar {
+		return false
+	}
+	rest := s[1:]
+	if rest == "" {
+		return true
+	}
+	b, _ := l.reader.Peek(len(rest))
+	return len(b) == len(rest) && string(b) == rest
+}
+
+// advanceN advances past n runes.
+func (l *Lexer) advanceN(n int) {
+	for i := 0; i < n; i++ {
+		l.advance()
+	}
+}
+
+// matchBlockCommentStart reports the first configured block-comment
+// delimiter pair starting at CurrentChar, if any.
+func (l *Lexer) matchBlockCommentStart() (CommentDelims, bool) {
+	for _, d := range l.Dialect.BlockComments {
+		if l.lookingAt(d.Open) {
+			return d, true
+		}
+	}
+	return CommentDelims{}, false
+}
+
+// skipBlockComment consumes a block comment opened by d.Open. An
+// unterminated comment is recorded as an error and treated as though its
+// closing delimiter had been found at end of file, so the caller can keep
+// scanning.
+func (l *Lexer) skipBlockComment(d CommentDelims) {
+	l.advanceN(utf8.RuneCountInString(d.Open))
+	for l.CurrentChar != NONE && !l.lookingAt(d.Close) {
+		l.advance()
+	}
+	if l.CurrentChar == NONE {
+		l.error(fmt.Sprintf("comment starting with %q not terminated before end of file", d.Open), "")
+		return
+	}
+	l.advanceN(utf8.RuneCountInString(d.Close))
+}
+
+// skipLineComment consumes a comment running from CurrentChar to the end
+// of the line.
+func (l *Lexer) skipLineComment() {
+	for l.CurrentChar != NONE && l.CurrentChar != '\n' && l.CurrentChar != '\r' {
+		l.advance()
+	}
+}
+
+// matchExtraOperator reports the first dialect-specific operator starting
+// at CurrentChar, if any.
+func (l *Lexer) matchExtraOperator() *DialectOperator {
+	for i, op := range l.Dialect.ExtraOperators {
+		if l.lookingAt(op.Op) {
+			return &l.Dialect.ExtraOperators[i]
+		}
+	}
+	return nil
+}
+
+func (l *Lexer) GetNextToken() Token {
+	for l.CurrentChar != NONE {
+		if l.isSpace(l.CurrentChar) {
+			l.skipWhiteSpace()
+			continue
+		}
+		if d, ok := l.matchBlockCommentStart(); ok {
+			l.skipBlockComment(d)
+			continue
+		}
+		if l.Dialect.LineComment != "" && l.lookingAt(l.Dialect.LineComment) {
+			l.skipLineComment()
+			continue
+		}
+		if l.isDigit(l.CurrentChar) {
+			return l.number()
+		}
+		if l.isLetter(l.CurrentChar) {
+			return l.identifier()
+		}
+		if matched := l.matchExtraOperator(); matched != nil {
+			op := *matched
+			pos := l.tokenPos()
+			l.advanceN(utf8.RuneCountInString(op.Op))
+			return Token{Type: op.Type, Value: op.Op, Pos: pos}
+		}
+		if l.CurrentChar == '+' {
+			pos := l.tokenPos()
+			l.advance()
+			return Token{Type: PLUS, Value: "+", Pos: pos}
+		}
+		if l.CurrentChar == '-' {
+			pos := l.tokenPos()
+			l.advance()
+			return Token{Type: MINUS, Value: "-", Pos: pos}
+		}
+		if l.CurrentChar == '*' {
+			pos := l.tokenPos()
+			l.advance()
+			return Token{Type: MUL, Value: "*", Pos: pos}
+		}
+		if l.CurrentChar == '/' {
+			pos := l.tokenPos()
+			l.advance()
+			return Token{Type: FLOAT_DIV, Value: "/", Pos: pos}
+		}
+		if l.CurrentChar == '(' {
+			pos := l.tokenPos()
+			l.advance()
+			return Token{Type: LPAREN, Value: "(", Pos: pos}
+		}
+		if l.CurrentChar == ')' {
+			pos := l.tokenPos()
+			l.advance()
+			return Token{Type: RPAREN, Value: ")", Pos: pos}
+		}
+		if l.CurrentChar == '.' {
+			pos := l.tokenPos()
+			l.advance()
+			return Token{Type: DOT, Value: ".", Pos: pos}
+		}
+		if l.CurrentChar == ',' {
+			pos := l.tokenPos()
+			l.advance()
+			return Token{Type: COMMA, Value: ",", Pos: pos}
+		}
+		if l.CurrentChar == ';' {
+			pos := l.tokenPos()
+			l.advance()
+			return Token{Type: SEMI, Value: ";", Pos: pos}
+		}
+		if l.CurrentChar == ':' {
+			pos := l.tokenPos()
+			if l.peek() == '=' {
+				l.advance()
+				l.advance()
+				return Token{Type: ASSIGN, Value: ":=", Pos: pos}
+			}
+			l.advance()
+			return Token{Type: COLON, Value: ":", Pos: pos}
+		}
+		if l.CurrentChar == '\r' || l.CurrentChar == '\n' {
+			l.advance()
+		} else {
+			l.error(fmt.Sprintf("unexpected character %q", l.CurrentChar), string(l.CurrentChar))
+			l.advance()
+		}
+	}
+	return Token{Type: EOF, Value: NONE, Pos: l.tokenPos()}
+}
+
+// Tokens returns an iterator over every token GetNextToken would produce,
+// ending with (and including) its EOF token. Unlike looping on
+// `tok.Value != NONE`, checking tok.Type == EOF can't be fooled by a
+// legitimate token whose Value happens to equal the NONE sentinel.
+func (l *Lexer) Tokens() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for {
+			tok := l.GetNextToken()
+			if !yield(tok) {
+				return
+			}
+			if tok.Type == EOF {
+				return
+			}
+		}
+	}
+}
+
+// TokenChan streams tokens over a channel, for callers that can't use
+// Tokens' range-over-func form. The channel receives the EOF token and is
+// then closed.
+func (l *Lexer) TokenChan() <-chan Token {
+	ch := make(chan Token)
+	go func() {
+		defer close(ch)
+		for {
+			tok := l.GetNextToken()
+			ch <- tok
+			if tok.Type == EOF {
+				return
+			}
+		}
+	}()
+	return ch
+}
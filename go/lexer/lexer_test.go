@@ -0,0 +1,293 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// syntheticPascalSource builds a multi-megabyte Standard Pascal program made
+// of n assignment statements mixing identifiers, integers, and reals.
+func syntheticPascalSource(n int) string {
+	var src strings.Builder
+	src.WriteString("PROGRAM Bench;\nVAR\nBEGIN\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&src, "  x%d := %d + %d.%d;\n", i, i, i, i)
+	}
+	src.WriteString("END.\n")
+	return src.String()
+}
+
+// identLits runs src through a StandardPascal lexer and returns the Lit of
+// every IDENT token it produces, in order.
+func identLits(t *testing.T, src string) []string {
+	t.Helper()
+	lex := NewLexerString(src)
+	var got []string
+	for tok := range lex.Tokens() {
+		if tok.Type == EOF {
+			break
+		}
+		if tok.Type == IDENT {
+			got = append(got, tok.Lit)
+		}
+	}
+	return got
+}
+
+func TestAdvanceConsumesOneCharacterPerNewline(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{"lf", "foo\nbar\nbaz", []string{"foo", "bar", "baz"}},
+		{"crlf", "foo\r\nbar\r\nbaz", []string{"foo", "bar", "baz"}},
+		{"cr", "foo\rbar\rbaz", []string{"foo", "bar", "baz"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := identLits(t, tc.src)
+			if len(got) != len(tc.want) {
+				t.Fatalf("identLits(%q) = %v, want %v", tc.src, got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("identLits(%q) = %v, want %v", tc.src, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilePositionAcrossLines(t *testing.T) {
+	lex := NewLexerString("foo\nbar baz")
+	var positions []Position
+	for tok := range lex.Tokens() {
+		if tok.Type == EOF {
+			break
+		}
+		positions = append(positions, lex.File().Position(tok.Pos))
+	}
+	want := []Position{
+		{Line: 1, Column: 1}, // foo
+		{Line: 2, Column: 1}, // bar
+		{Line: 2, Column: 5}, // baz
+	}
+	if len(positions) != len(want) {
+		t.Fatalf("got %d tokens %+v, want %+v", len(positions), positions, want)
+	}
+	for i, p := range positions {
+		if p.Line != want[i].Line || p.Column != want[i].Column {
+			t.Fatalf("token %d position = %+v, want %+v", i, p, want[i])
+		}
+	}
+}
+
+func TestErrorReportsCorrectColumn(t *testing.T) {
+	lex := NewLexerString("abc\n  @")
+	for tok := lex.GetNextToken(); tok.Type != EOF; tok = lex.GetNextToken() {
+	}
+	if len(lex.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(lex.Errors), lex.Errors)
+	}
+	if got := lex.Errors[0]; got.Line != 2 || got.Column != 3 {
+		t.Fatalf("error position = %d:%d, want 2:3", got.Line, got.Column)
+	}
+}
+
+func TestDialectsVaryKeywordCaseAndComments(t *testing.T) {
+	src := "program Foo; // trailing comment\n{ block } (* also block *) begin end."
+
+	standard := NewLexerWithDialect("", strings.NewReader(src), StandardPascal)
+	if got := standard.GetNextToken(); got.Type != IDENT || got.Lit != "program" {
+		t.Fatalf("StandardPascal is case-sensitive: got %+v, want IDENT %q", got, "program")
+	}
+
+	turbo := NewLexerWithDialect("", strings.NewReader(src), TurboPascal)
+	var types []TokenType
+	for tok := range turbo.Tokens() {
+		if tok.Type == EOF {
+			break
+		}
+		types = append(types, tok.Type)
+	}
+	want := []TokenType{PROGRAM, IDENT, SEMI, BEGIN, END, DOT}
+	if len(types) != len(want) {
+		t.Fatalf("TurboPascal tokens = %v, want %v", types, want)
+	}
+	for i := range types {
+		if types[i] != want[i] {
+			t.Fatalf("TurboPascal tokens = %v, want %v", types, want)
+		}
+	}
+	if turbo.HasErrors() {
+		t.Fatalf("TurboPascal should skip both { } and (* *) comments, got errors: %v", turbo.Errors)
+	}
+}
+
+func TestDialectExtraOperators(t *testing.T) {
+	lex := NewLexerWithDialect("", strings.NewReader("1 <= 2 <> 3 .. 4"), TurboPascal)
+	var types []TokenType
+	for tok := range lex.Tokens() {
+		if tok.Type == EOF {
+			break
+		}
+		if tok.Type != INTEGER_CONST {
+			types = append(types, tok.Type)
+		}
+	}
+	want := []TokenType{LE, NE, RANGE}
+	if len(types) != len(want) {
+		t.Fatalf("got operators %v, want %v", types, want)
+	}
+	for i := range types {
+		if types[i] != want[i] {
+			t.Fatalf("got operators %v, want %v", types, want)
+		}
+	}
+}
+
+func TestNumberAndIdentifierLiterals(t *testing.T) {
+	lex := NewLexerString("counter 42 3.14")
+
+	ident := lex.GetNextToken()
+	if ident.Type != IDENT || ident.Lit != "counter" {
+		t.Fatalf("got %+v, want IDENT %q", ident, "counter")
+	}
+
+	intTok := lex.GetNextToken()
+	if intTok.Type != INTEGER_CONST || intTok.Lit != "42" {
+		t.Fatalf("got %+v, want INTEGER_CONST %q", intTok, "42")
+	}
+	if v, err := intTok.IntVal(); err != nil || v != 42 {
+		t.Fatalf("IntVal() = %d, %v, want 42, nil", v, err)
+	}
+
+	realTok := lex.GetNextToken()
+	if realTok.Type != REAL_CONST || realTok.Lit != "3.14" {
+		t.Fatalf("got %+v, want REAL_CONST %q", realTok, "3.14")
+	}
+	if v, err := realTok.FloatVal(); err != nil || v != 3.14 {
+		t.Fatalf("FloatVal() = %v, %v, want 3.14, nil", v, err)
+	}
+}
+
+func TestAssignmentAndSingleDigitRealLiteral(t *testing.T) {
+	lex := NewLexerString("x := 1 + 2")
+	want := []struct {
+		typ TokenType
+		lit string
+	}{
+		{IDENT, "x"},
+		{ASSIGN, ""},
+		{INTEGER_CONST, "1"},
+		{PLUS, ""},
+		{INTEGER_CONST, "2"},
+	}
+	for i, w := range want {
+		tok := lex.GetNextToken()
+		if tok.Type != w.typ || (w.lit != "" && tok.Lit != w.lit) {
+			t.Fatalf("token %d = %+v, want type %q lit %q", i, tok, w.typ, w.lit)
+		}
+	}
+	if lex.HasErrors() {
+		t.Fatalf("unexpected errors: %v", lex.Errors)
+	}
+
+	for _, src := range []string{"3.4", "3.4;"} {
+		lex := NewLexerString(src)
+		tok := lex.GetNextToken()
+		if tok.Type != REAL_CONST || tok.Lit != "3.4" {
+			t.Fatalf("lexing %q: got %+v, want REAL_CONST %q", src, tok, "3.4")
+		}
+	}
+}
+
+func TestNonASCIIIdentifier(t *testing.T) {
+	lex := NewLexerString("café + 1")
+	tok := lex.GetNextToken()
+	if tok.Type != IDENT || tok.Lit != "café" {
+		t.Fatalf("got %+v, want IDENT %q", tok, "café")
+	}
+}
+
+func TestLeadingBOMIsSkipped(t *testing.T) {
+	lex := NewLexerString("\uFEFF" + "foo")
+	tok := lex.GetNextToken()
+	if tok.Type != IDENT || tok.Lit != "foo" {
+		t.Fatalf("got %+v, want IDENT %q", tok, "foo")
+	}
+	if pos := lex.File().Position(tok.Pos); pos.Column != 1 {
+		t.Fatalf("BOM should not count toward the first token's column, got column %d", pos.Column)
+	}
+}
+
+func TestTokensIteratorDoesNotStopOn255(t *testing.T) {
+	// NONE, the exhausted-input sentinel, is -1, but a naive
+	// `tok.Value != NONE` loop compares against an interface{}, and the
+	// int 255 is what a careless cast of NONE to a byte would produce;
+	// this regresses that class of bug by making sure a literal 255
+	// doesn't end the stream early.
+	lex := NewLexerString("1 255 2")
+	var lits []string
+	for tok := range lex.Tokens() {
+		if tok.Type == EOF {
+			break
+		}
+		lits = append(lits, tok.Lit)
+	}
+	want := []string{"1", "255", "2"}
+	if len(lits) != len(want) {
+		t.Fatalf("Tokens() yielded %v, want %v", lits, want)
+	}
+	for i := range lits {
+		if lits[i] != want[i] {
+			t.Fatalf("Tokens() yielded %v, want %v", lits, want)
+		}
+	}
+}
+
+func TestTokenChanMatchesTokensIterator(t *testing.T) {
+	const src = "x := 1 + 2"
+	var viaTokens []TokenType
+	for tok := range NewLexerString(src).Tokens() {
+		viaTokens = append(viaTokens, tok.Type)
+	}
+
+	var viaChan []TokenType
+	for tok := range NewLexerString(src).TokenChan() {
+		viaChan = append(viaChan, tok.Type)
+	}
+
+	if len(viaTokens) != len(viaChan) {
+		t.Fatalf("Tokens() yielded %v, TokenChan() yielded %v", viaTokens, viaChan)
+	}
+	for i := range viaTokens {
+		if viaTokens[i] != viaChan[i] {
+			t.Fatalf("Tokens() yielded %v, TokenChan() yielded %v", viaTokens, viaChan)
+		}
+	}
+}
+
+// BenchmarkGetNextToken reports the absolute allocs/op of scanning a
+// multi-megabyte source; there's no earlier, slice-backed implementation
+// retained in this tree to diff against; see the allocation tradeoff noted
+// on number/identifier for why this one allocates a string per literal
+// instead of slicing the source.
+func BenchmarkGetNextToken(b *testing.B) {
+	src := syntheticPascalSource(100000) // about 3.3 MB of source
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		lex := NewLexerString(src)
+		for {
+			tok := lex.GetNextToken()
+			if tok.Type == EOF {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Irwin1985/LexerCollection/go/lexer"
+)
+
+func main() {
+	pwd, _ := os.Getwd()
+	f, err := os.Open(pwd + "/test.txt")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer f.Close()
+
+	lex := lexer.NewLexer("test.txt", f)
+	for tok := range lex.Tokens() {
+		if tok.Type == lexer.EOF {
+			break
+		}
+		fmt.Printf("%s: %-v\n", lex.File().Position(tok.Pos), tok)
+	}
+
+	if lex.HasErrors() {
+		fmt.Println("\nDiagnostics:")
+		for _, e := range lex.Errors {
+			fmt.Println(e.Error())
+		}
+	}
+}